@@ -2,13 +2,19 @@
 # Build: ./run_container.sh build -f example-forge-files/Forgefile.go -t go-app:v1.0
 # Run:   ./run_container.sh run go-app:v1.0
 #
-# Note: Go compiles to a static binary, so multi-stage builds
-# would allow a much smaller final image (just the binary).
+# Two-stage build: compile the static binary in a full golang image,
+# then copy just the binary into a slim alpine runtime so the Go
+# toolchain never ends up in the final image.
+#
+# Override either ARG with --build-arg, e.g.
+#   ./run_container.sh build -f example-forge-files/Forgefile.go \
+#     --build-arg GO_VERSION=1.22-alpine --build-arg VERSION=1.2.3 -t go-app:v1.0
 
-FROM alpine:3.19
+ARG GO_VERSION="alpine"
+
+FROM golang:${GO_VERSION} AS builder
 
-# Install Go
-RUN apk add --no-cache go
+ARG VERSION="0.0.0"
 
 WORKDIR /app
 
@@ -21,8 +27,24 @@ RUN go mod download
 
 # Copy source code
 COPY main.go /app/
+COPY main_test.go /app/
+
+# Fail the build before an image is ever produced if the test suite
+# doesn't pass
+TEST go test ./...
+
+# Build static binary, stamping in the requested version
+RUN CGO_ENABLED=0 go build -ldflags="-X 'main.Version=$VERSION'" -o app main.go
 
-# Build static binary
-RUN CGO_ENABLED=0 go build -o app main.go
+# Shrink the binary 60-70% before it lands in the runtime stage
+COMPRESS app --level=best
+
+FROM alpine:3.19
+
+# Run as a dedicated, unprivileged user rather than root
+RUNAS appuser --uid=10001 --no-home
+
+WORKDIR /app
+COPY --from=builder /app/app /app/app
 
 ENTRYPOINT ["./app"]
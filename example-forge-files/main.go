@@ -0,0 +1,10 @@
+package main
+
+import "fmt"
+
+// Version is stamped at build time via -ldflags "-X 'main.Version=...'".
+var Version = "dev"
+
+func main() {
+	fmt.Printf("go-app %s\n", Version)
+}
@@ -0,0 +1,9 @@
+package main
+
+import "testing"
+
+func TestVersionDefault(t *testing.T) {
+	if Version == "" {
+		t.Fatal("Version must not be empty")
+	}
+}